@@ -0,0 +1,150 @@
+// Copyright 2017 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"path/filepath"
+	"strconv"
+	"text/template"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// dashboardDockerfile is the Dockerfile required to serve the static network
+// dashboard page.
+var dashboardDockerfile = `
+FROM nginx:1.13
+
+ADD dashboard /usr/share/nginx/html
+`
+
+// dashboardComposefile is the docker-compose.yml file required to deploy and
+// maintain a network dashboard.
+var dashboardComposefile = `
+version: '2'
+services:
+  dashboard:
+    build: .
+    image: {{.Network}}/dashboard
+    container_name: {{.Network}}_dashboard_1
+    ports:
+      - "{{.Port}}:80"
+    logging:
+      driver: "json-file"
+      options:
+        max-size: "1m"
+        max-file: "10"
+    restart: always
+`
+
+// dashboardContent is the static HTML template rendered into the dashboard
+// image, aggregating the swarm/enode links of the network's nodes and any
+// auxiliary services deployed alongside them.
+var dashboardContent = `<!DOCTYPE html>
+<html>
+<head><title>{{.Network}} swarm network</title></head>
+<body>
+  <h1>{{.Network}}</h1>
+  {{if .Ethstats}}<p>Ethstats: <a href="{{.Ethstats}}">{{.Ethstats}}</a></p>{{end}}
+  {{if .Explorer}}<p>Explorer: <a href="{{.Explorer}}">{{.Explorer}}</a></p>{{end}}
+  {{if .Faucet}}<p>Faucet: <a href="{{.Faucet}}">{{.Faucet}}</a></p>{{end}}
+  <h2>Swarm nodes</h2>
+  <ul>
+  {{range .Enodes}}<li><code>{{.}}</code></li>
+  {{end}}
+  </ul>
+</body>
+</html>
+`
+
+// dashboardEntries bundles what the dashboard template needs to render the
+// current state of the network.
+type dashboardEntries struct {
+	Network  string
+	Enodes   []string
+	Ethstats string
+	Explorer string
+	Faucet   string
+}
+
+// deployDashboard deploys a new dashboard container to a remote machine via
+// SSH, docker and docker-compose. If an instance with the specified network
+// name already exists there, it will be overwritten!
+func deployDashboard(client *sshClient, network string, port int, entries *dashboardEntries) ([]byte, error) {
+	workdir := fmt.Sprintf("%d", rand.Int63())
+	files := make(map[string][]byte)
+
+	dockerfile := new(bytes.Buffer)
+	template.Must(template.New("").Parse(dashboardDockerfile)).Execute(dockerfile, nil)
+	files[filepath.Join(workdir, "Dockerfile")] = dockerfile.Bytes()
+
+	composefile := new(bytes.Buffer)
+	template.Must(template.New("").Parse(dashboardComposefile)).Execute(composefile, map[string]interface{}{
+		"Network": network,
+		"Port":    port,
+	})
+	files[filepath.Join(workdir, "docker-compose.yaml")] = composefile.Bytes()
+
+	content := new(bytes.Buffer)
+	template.Must(template.New("").Parse(dashboardContent)).Execute(content, entries)
+	files[filepath.Join(workdir, "dashboard", "index.html")] = content.Bytes()
+
+	if out, err := client.Upload(files); err != nil {
+		return out, err
+	}
+	defer client.Run("rm -rf " + workdir)
+
+	return nil, client.Stream(fmt.Sprintf("cd %s && docker-compose -p %s up -d --build --force-recreate", workdir, network))
+}
+
+// dashboardInfos is returned from a dashboard status check to allow reporting
+// various configuration parameters.
+type dashboardInfos struct {
+	host string
+	port int
+}
+
+// Report converts the typed struct into a plain string->string map, containing
+// most - but not all - fields for reporting to the user.
+func (info *dashboardInfos) Report() map[string]string {
+	return map[string]string{
+		"Website address":       info.host,
+		"Website listener port": strconv.Itoa(info.port),
+	}
+}
+
+// checkDashboard does a health-check against a dashboard server to verify
+// whether it's running, and if yes, whether it's responsive.
+func checkDashboard(client *sshClient, network string) (*dashboardInfos, error) {
+	infos, err := inspectContainer(client, fmt.Sprintf("%s_dashboard_1", network))
+	if err != nil {
+		return nil, err
+	}
+	if !infos.running {
+		return nil, ErrServiceOffline
+	}
+	if err = checkPort(client.server, infos.portmap["80/tcp"]); err != nil {
+		log.Warn("Dashboard service seems unreachable", "server", client.server, "err", err)
+	}
+	return &dashboardInfos{
+		host: client.server,
+		port: infos.portmap["80/tcp"],
+	}, nil
+}
@@ -0,0 +1,140 @@
+// Copyright 2017 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// ethstatsDockerfile is the Dockerfile required to run an ethstats monitoring
+// component for a swarm network.
+var ethstatsDockerfile = `
+FROM mhart/alpine-node:6
+
+RUN \
+  apk add --update git                                        && \
+  git clone --depth=1 https://github.com/cubedro/eth-netstats  && \
+  (cd eth-netstats && npm install && npm install -g grunt-cli && grunt)
+
+WORKDIR /eth-netstats
+EXPOSE 3000
+
+ENTRYPOINT ["npm", "start"]
+`
+
+// ethstatsComposefile is the docker-compose.yml file required to deploy and
+// maintain an ethstats monitoring component.
+var ethstatsComposefile = `
+version: '2'
+services:
+  ethstats:
+    build: .
+    image: {{.Network}}/ethstats
+    container_name: {{.Network}}_ethstats_1
+    ports:
+      - "{{.Port}}:3000"
+    environment:
+      - WS_SECRET={{.Secret}}
+      - BANNED={{.Banned}}
+    logging:
+      driver: "json-file"
+      options:
+        max-size: "1m"
+        max-file: "10"
+    restart: always
+`
+
+// deployEthstats deploys a new ethstats monitoring component to a remote
+// machine via SSH, docker and docker-compose. If an instance with the
+// specified network name already exists there, it will be overwritten!
+func deployEthstats(client *sshClient, network string, port int, secret string, banned []string) ([]byte, error) {
+	workdir := fmt.Sprintf("%d", rand.Int63())
+	files := make(map[string][]byte)
+
+	dockerfile := new(bytes.Buffer)
+	template.Must(template.New("").Parse(ethstatsDockerfile)).Execute(dockerfile, nil)
+	files[filepath.Join(workdir, "Dockerfile")] = dockerfile.Bytes()
+
+	composefile := new(bytes.Buffer)
+	template.Must(template.New("").Parse(ethstatsComposefile)).Execute(composefile, map[string]interface{}{
+		"Network": network,
+		"Port":    port,
+		"Secret":  secret,
+		"Banned":  strings.Join(banned, ","),
+	})
+	files[filepath.Join(workdir, "docker-compose.yaml")] = composefile.Bytes()
+
+	if out, err := client.Upload(files); err != nil {
+		return out, err
+	}
+	defer client.Run("rm -rf " + workdir)
+
+	return nil, client.Stream(fmt.Sprintf("cd %s && docker-compose -p %s up -d --build --force-recreate", workdir, network))
+}
+
+// ethstatsInfos is returned from an ethstats status check to allow reporting
+// various configuration parameters.
+type ethstatsInfos struct {
+	host   string
+	port   int
+	secret string
+	banned []string
+}
+
+// Report converts the typed struct into a plain string->string map, containing
+// most - but not all - fields for reporting to the user.
+func (info *ethstatsInfos) Report() map[string]string {
+	return map[string]string{
+		"Website address":       info.host,
+		"Website listener port": strconv.Itoa(info.port),
+		"Login secret":          info.secret,
+		"Banned addresses":      strings.Join(info.banned, "\n"),
+	}
+}
+
+// checkEthstats does a health-check against an ethstats server to verify
+// whether it's running, and if yes, whether it's responsive.
+func checkEthstats(client *sshClient, network string) (*ethstatsInfos, error) {
+	infos, err := inspectContainer(client, fmt.Sprintf("%s_ethstats_1", network))
+	if err != nil {
+		return nil, err
+	}
+	if !infos.running {
+		return nil, ErrServiceOffline
+	}
+	if err = checkPort(client.server, infos.portmap["3000/tcp"]); err != nil {
+		log.Warn("Ethstats service seems unreachable", "server", client.server, "err", err)
+	}
+	banned := []string{}
+	if raw := infos.envvars["BANNED"]; raw != "" {
+		banned = strings.Split(raw, ",")
+	}
+	return &ethstatsInfos{
+		host:   client.server,
+		port:   infos.portmap["3000/tcp"],
+		secret: infos.envvars["WS_SECRET"],
+		banned: banned,
+	}, nil
+}
@@ -0,0 +1,145 @@
+// Copyright 2017 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// explorerDockerfile is the Dockerfile required to run a block explorer.
+var explorerDockerfile = `
+FROM ethereum/client-go:alltools-v1.8.3 as geth
+
+FROM etherparty/explorer
+
+COPY --from=geth /usr/local/bin/geth /usr/local/bin/geth
+ADD genesis.json /genesis.json
+
+RUN \
+	echo 'geth --datadir /root/.ethereum init /genesis.json' > explorer.sh && \
+	echo $'geth --networkid {{.NetworkID}} --datadir /root/.ethereum --port {{.Port}} --maxpeers 50 --rpc --rpcaddr 0.0.0.0 --rpcvhosts "*" --rpcapi eth,net,web3 {{if .Bootnodes}}--bootnodes {{.Bootnodes}}{{end}} &' >> explorer.sh && \
+	echo 'npm start' >> explorer.sh
+
+ENTRYPOINT ["/bin/sh", "explorer.sh"]
+`
+
+// explorerComposefile is the docker-compose.yml file required to deploy and
+// maintain a block explorer.
+var explorerComposefile = `
+version: '2'
+services:
+  explorer:
+    build: .
+    image: {{.Network}}/explorer
+    container_name: {{.Network}}_explorer_1
+    ports:
+      - "{{.Port}}:8080"
+      - "{{.EthPort}}:{{.EthPort}}"
+      - "{{.EthPort}}:{{.EthPort}}/udp"
+    volumes:
+      - {{.Datadir}}:/root/.ethereum
+    logging:
+      driver: "json-file"
+      options:
+        max-size: "1m"
+        max-file: "10"
+    restart: always
+`
+
+// deployExplorer deploys a new block explorer container to a remote machine
+// via SSH, docker and docker-compose. If an instance with the specified
+// network name already exists there, it will be overwritten!
+func deployExplorer(client *sshClient, network string, bootnodes []string, config *explorerInfos) ([]byte, error) {
+	workdir := fmt.Sprintf("%d", rand.Int63())
+	files := make(map[string][]byte)
+
+	dockerfile := new(bytes.Buffer)
+	template.Must(template.New("").Parse(explorerDockerfile)).Execute(dockerfile, map[string]interface{}{
+		"NetworkID": config.networkID,
+		"Port":      config.ethPort,
+		"Bootnodes": strings.Join(bootnodes, ","),
+	})
+	files[filepath.Join(workdir, "Dockerfile")] = dockerfile.Bytes()
+
+	composefile := new(bytes.Buffer)
+	template.Must(template.New("").Parse(explorerComposefile)).Execute(composefile, map[string]interface{}{
+		"Network": network,
+		"Port":    config.port,
+		"EthPort": config.ethPort,
+		"Datadir": config.datadir,
+	})
+	files[filepath.Join(workdir, "docker-compose.yaml")] = composefile.Bytes()
+
+	files[filepath.Join(workdir, "genesis.json")] = config.genesis
+
+	if out, err := client.Upload(files); err != nil {
+		return out, err
+	}
+	defer client.Run("rm -rf " + workdir)
+
+	return nil, client.Stream(fmt.Sprintf("cd %s && docker-compose -p %s up -d --build --force-recreate", workdir, network))
+}
+
+// explorerInfos is returned from an explorer status check to allow reporting
+// various configuration parameters.
+type explorerInfos struct {
+	genesis   []byte
+	networkID int64
+	datadir   string
+	port      int
+	ethPort   int
+	host      string
+}
+
+// Report converts the typed struct into a plain string->string map, containing
+// most - but not all - fields for reporting to the user.
+func (info *explorerInfos) Report() map[string]string {
+	return map[string]string{
+		"Website address":        info.host,
+		"Website listener port":  strconv.Itoa(info.port),
+		"Ethereum listener port": strconv.Itoa(info.ethPort),
+		"Data directory":         info.datadir,
+	}
+}
+
+// checkExplorer does a health-check against an explorer server to verify
+// whether it's running, and if yes, whether it's responsive.
+func checkExplorer(client *sshClient, network string) (*explorerInfos, error) {
+	infos, err := inspectContainer(client, fmt.Sprintf("%s_explorer_1", network))
+	if err != nil {
+		return nil, err
+	}
+	if !infos.running {
+		return nil, ErrServiceOffline
+	}
+	if err = checkPort(client.server, infos.portmap["8080/tcp"]); err != nil {
+		log.Warn("Explorer service seems unreachable", "server", client.server, "err", err)
+	}
+	return &explorerInfos{
+		datadir: infos.volumes["/root/.ethereum"],
+		port:    infos.portmap["8080/tcp"],
+		host:    client.server,
+	}, nil
+}
@@ -0,0 +1,160 @@
+// Copyright 2017 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// faucetDockerfile is the Dockerfile required to build a faucet for a given
+// network, funding requests from a pre-funded account.
+var faucetDockerfile = `
+FROM ethereum/client-go:alltools-v1.8.3
+
+ADD genesis.json /genesis.json
+ADD account.json /account.json
+ADD account.pass /account.pass
+
+RUN \
+	echo 'geth --datadir /root/.ethereum init /genesis.json' > faucet.sh && \
+	echo 'mkdir -p /root/.ethereum/keystore/ && cp /account.json /root/.ethereum/keystore/' >> faucet.sh && \
+	echo $'faucet --genesis /genesis.json --network {{.NetworkID}} --bootnodes {{.Bootnodes}} --account.json /account.json --account.pass /account.pass --faucet.amount {{.Amount}} --faucet.minutes {{.Minutes}} --faucet.tiers {{.Tiers}}' >> faucet.sh
+
+ENTRYPOINT ["/bin/sh", "faucet.sh"]
+`
+
+// faucetComposefile is the docker-compose.yml file required to deploy and
+// maintain a faucet.
+var faucetComposefile = `
+version: '2'
+services:
+  faucet:
+    build: .
+    image: {{.Network}}/faucet
+    container_name: {{.Network}}_faucet_1
+    ports:
+      - "{{.Port}}:8080"
+    environment:
+      - FAUCET_AMOUNT={{.Amount}}
+      - FAUCET_MINUTES={{.Minutes}}
+      - FAUCET_TIERS={{.Tiers}}
+    logging:
+      driver: "json-file"
+      options:
+        max-size: "1m"
+        max-file: "10"
+    restart: always
+`
+
+// deployFaucet deploys a new faucet container to a remote machine via SSH,
+// docker and docker-compose. If an instance with the specified network name
+// already exists there, it will be overwritten!
+func deployFaucet(client *sshClient, network string, bootnodes []string, config *faucetInfos) ([]byte, error) {
+	workdir := fmt.Sprintf("%d", rand.Int63())
+	files := make(map[string][]byte)
+
+	dockerfile := new(bytes.Buffer)
+	template.Must(template.New("").Parse(faucetDockerfile)).Execute(dockerfile, map[string]interface{}{
+		"NetworkID": config.networkID,
+		"Bootnodes": strings.Join(bootnodes, ","),
+		"Amount":    config.amount,
+		"Minutes":   config.minutes,
+		"Tiers":     config.tiers,
+	})
+	files[filepath.Join(workdir, "Dockerfile")] = dockerfile.Bytes()
+
+	composefile := new(bytes.Buffer)
+	template.Must(template.New("").Parse(faucetComposefile)).Execute(composefile, map[string]interface{}{
+		"Network": network,
+		"Port":    config.port,
+		"Amount":  config.amount,
+		"Minutes": config.minutes,
+		"Tiers":   config.tiers,
+	})
+	files[filepath.Join(workdir, "docker-compose.yaml")] = composefile.Bytes()
+
+	files[filepath.Join(workdir, "genesis.json")] = config.genesis
+	files[filepath.Join(workdir, "account.json")] = []byte(config.keyJSON)
+	files[filepath.Join(workdir, "account.pass")] = []byte(config.keyPass)
+
+	if out, err := client.Upload(files); err != nil {
+		return out, err
+	}
+	defer client.Run("rm -rf " + workdir)
+
+	return nil, client.Stream(fmt.Sprintf("cd %s && docker-compose -p %s up -d --build --force-recreate", workdir, network))
+}
+
+// faucetInfos is returned from a faucet status check to allow reporting
+// various configuration parameters.
+type faucetInfos struct {
+	genesis   []byte
+	networkID int64
+	port      int
+	host      string
+	amount    int
+	minutes   int
+	tiers     int
+	keyJSON   string
+	keyPass   string
+}
+
+// Report converts the typed struct into a plain string->string map, containing
+// most - but not all - fields for reporting to the user.
+func (info *faucetInfos) Report() map[string]string {
+	return map[string]string{
+		"Website address":  info.host,
+		"Listener port":    strconv.Itoa(info.port),
+		"Funding amount":   strconv.Itoa(info.amount),
+		"Funding cooldown": strconv.Itoa(info.minutes) + "m",
+		"Funding tiers":    strconv.Itoa(info.tiers),
+	}
+}
+
+// checkFaucet does a health-check against a faucet server to verify whether
+// it's running, and if yes, whether it's responsive.
+func checkFaucet(client *sshClient, network string) (*faucetInfos, error) {
+	infos, err := inspectContainer(client, fmt.Sprintf("%s_faucet_1", network))
+	if err != nil {
+		return nil, err
+	}
+	if !infos.running {
+		return nil, ErrServiceOffline
+	}
+	if err = checkPort(client.server, infos.portmap["8080/tcp"]); err != nil {
+		log.Warn("Faucet service seems unreachable", "server", client.server, "err", err)
+	}
+	amount, _ := strconv.Atoi(infos.envvars["FAUCET_AMOUNT"])
+	minutes, _ := strconv.Atoi(infos.envvars["FAUCET_MINUTES"])
+	tiers, _ := strconv.Atoi(infos.envvars["FAUCET_TIERS"])
+
+	return &faucetInfos{
+		port:    infos.portmap["8080/tcp"],
+		host:    client.server,
+		amount:  amount,
+		minutes: minutes,
+		tiers:   tiers,
+	}, nil
+}
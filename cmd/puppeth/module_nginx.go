@@ -0,0 +1,145 @@
+// Copyright 2017 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"path/filepath"
+	"strconv"
+	"text/template"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// nginxDockerfile is the Dockerfile required to run an nginx reverse proxy in
+// front of the other swarm network services.
+var nginxDockerfile = `
+FROM nginx:1.13
+
+ADD nginx.conf /etc/nginx/nginx.conf
+`
+
+// nginxComposefile is the docker-compose.yml file required to deploy and
+// maintain an nginx reverse proxy.
+var nginxComposefile = `
+version: '2'
+services:
+  nginx:
+    build: .
+    image: {{.Network}}/nginx
+    container_name: {{.Network}}_nginx_1
+    network_mode: host
+    logging:
+      driver: "json-file"
+      options:
+        max-size: "1m"
+        max-file: "10"
+    restart: always
+`
+
+// nginxConfig is the nginx.conf template rendering a vhost-routed server
+// block per entry in the given vhost list.
+var nginxConfig = `
+events {}
+http {
+  {{range .Vhosts}}server {
+    listen 80;
+    server_name {{.Host}};
+    location / {
+      proxy_pass http://127.0.0.1:{{.Port}};
+    }
+  }
+  {{end}}
+}
+`
+
+// nginxVhost maps a virtual host name to the local port of the service it
+// should be proxied to.
+type nginxVhost struct {
+	Host string
+	Port int
+}
+
+// deployNginx deploys a new nginx reverse proxy container to a remote machine
+// via SSH, docker and docker-compose. If an instance with the specified
+// network name already exists there, it will be overwritten!
+func deployNginx(client *sshClient, network string, vhosts []nginxVhost) ([]byte, error) {
+	workdir := fmt.Sprintf("%d", rand.Int63())
+	files := make(map[string][]byte)
+
+	dockerfile := new(bytes.Buffer)
+	template.Must(template.New("").Parse(nginxDockerfile)).Execute(dockerfile, nil)
+	files[filepath.Join(workdir, "Dockerfile")] = dockerfile.Bytes()
+
+	composefile := new(bytes.Buffer)
+	template.Must(template.New("").Parse(nginxComposefile)).Execute(composefile, map[string]interface{}{
+		"Network": network,
+	})
+	files[filepath.Join(workdir, "docker-compose.yaml")] = composefile.Bytes()
+
+	conf := new(bytes.Buffer)
+	template.Must(template.New("").Parse(nginxConfig)).Execute(conf, map[string]interface{}{
+		"Vhosts": vhosts,
+	})
+	files[filepath.Join(workdir, "nginx.conf")] = conf.Bytes()
+
+	if out, err := client.Upload(files); err != nil {
+		return out, err
+	}
+	defer client.Run("rm -rf " + workdir)
+
+	return nil, client.Stream(fmt.Sprintf("cd %s && docker-compose -p %s up -d --build --force-recreate", workdir, network))
+}
+
+// nginxInfos is returned from an nginx status check to allow reporting
+// various configuration parameters.
+type nginxInfos struct {
+	host   string
+	vhosts []nginxVhost
+}
+
+// Report converts the typed struct into a plain string->string map, containing
+// most - but not all - fields for reporting to the user.
+func (info *nginxInfos) Report() map[string]string {
+	report := map[string]string{
+		"Proxy address": info.host,
+	}
+	for _, vhost := range info.vhosts {
+		report["Vhost "+vhost.Host] = strconv.Itoa(vhost.Port)
+	}
+	return report
+}
+
+// checkNginx does a health-check against an nginx server to verify whether
+// it's running, and if yes, whether it's responsive.
+func checkNginx(client *sshClient, network string) (*nginxInfos, error) {
+	infos, err := inspectContainer(client, fmt.Sprintf("%s_nginx_1", network))
+	if err != nil {
+		return nil, err
+	}
+	if !infos.running {
+		return nil, ErrServiceOffline
+	}
+	if err = checkPort(client.server, 80); err != nil {
+		log.Warn("Nginx service seems unreachable", "server", client.server, "err", err)
+	}
+	return &nginxInfos{
+		host: client.server,
+	}, nil
+}
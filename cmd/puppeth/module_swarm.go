@@ -18,29 +18,45 @@ package main
 
 import (
 	"bytes"
+	"crypto/ecdsa"
+	crand "crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"math/rand"
+	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
 	"text/template"
 
+	"github.com/ethereum/go-ethereum/accounts/keystore"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/naoina/toml"
 )
 
+// swarmKnownImage is the default client image used when the wizard isn't
+// told to pin a specific one.
+const swarmKnownImage = "ethereum/client-go"
+
+// swarmKnownVersion is the default client tag, pinned to the last swarm build
+// this simulator was verified against.
+const swarmKnownVersion = "alltools-v1.8.3"
+
 // nodeDockerfile is the Dockerfile required to run an Ethereum node.
 var swarmDockerfile = `
-FROM ethereum/client-go:alltools-v1.8.3
+FROM {{.Image}}
 
 ADD genesis.json /genesis.json
-ADD bzzkey.json /bzzkey.json
+{{if .Unlock}}ADD bzzkey.json /bzzkey.json
 ADD bzzpass /bzzpass
-
+{{end}}
 RUN \
-	echo 'mkdir -p /root/.ethereum/keystore/ && cp /bzzkey.json /root/.ethereum/keystore/' > geth.sh && \
-	echo $'swarm --bzznetworkid {{.NetworkID}} {{if .BzzAccount}}--bzzaccount {{.BzzAccount}} {{end}}--port {{.Port}} --bzzport {{.bzzPort}} --maxpeers {{.Peers}} {{if .SwarmBoot}}--bootnodes {{.SwarmBoot}}{{end}} --password /bzzpass' >> geth.sh
+	echo 'mkdir -p /root/.ethereum/keystore/ {{if .Unlock}}&& cp /bzzkey.json /root/.ethereum/keystore/{{end}}' > geth.sh && \
+	echo $'swarm --bzznetworkid {{.NetworkID}} {{if .BzzAccount}}--bzzaccount {{.BzzAccount}} {{end}}--port {{.Port}} --bzzport {{.bzzPort}} --maxpeers {{.Peers}} {{if .SwarmBoot}}--bootnodes {{.SwarmBoot}}{{end}}{{if .Unlock}} --password /bzzpass{{end}}{{if .EnsDisabled}} --ens-api ""{{else}}{{range .EnsAPIs}} --ens-api {{.}}{{end}}{{end}}{{if .ExtraFlags}} {{.ExtraFlags}}{{end}}' >> geth.sh
 
 ENTRYPOINT ["/bin/sh", "geth.sh"]
 `
@@ -60,6 +76,11 @@ services:
       - {{.Datadir}}:/root/.ethereum
     environment:
       - PORT={{.bzzPort}}/tcp
+      - CLIENT_IMAGE={{.ClientImage}}
+      - CLIENT_VERSION={{.ClientVersion}}
+      - EXTRA_FLAGS={{.ExtraFlags}}
+      - ENS_APIS={{.EnsAPIs}}
+      - ENS_DISABLED={{.EnsDisabled}}
     logging:
       driver: "json-file"
       options:
@@ -68,6 +89,59 @@ services:
     restart: always
 `
 
+// randomPassphrase generates a random passphrase suitable for protecting an
+// auto-generated or freshly imported bzzaccount key, for when the operator
+// doesn't want to (or can't) supply one interactively.
+func randomPassphrase() string {
+	buf := make([]byte, 16)
+	crand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+// encryptBzzKey wraps an ECDSA private key into an encrypted keystore JSON
+// blob, going through a throwaway on-disk keystore since that's the only way
+// accounts/keystore exposes to produce the on-the-wire format.
+func encryptBzzKey(priv *ecdsa.PrivateKey, passphrase string) ([]byte, common.Address, error) {
+	dir, err := ioutil.TempDir("", "bzzkey-")
+	if err != nil {
+		return nil, common.Address{}, err
+	}
+	defer os.RemoveAll(dir)
+
+	ks := keystore.NewKeyStore(dir, keystore.StandardScryptN, keystore.StandardScryptP)
+	account, err := ks.ImportECDSA(priv, passphrase)
+	if err != nil {
+		return nil, common.Address{}, err
+	}
+	keyJSON, err := ioutil.ReadFile(account.URL.Path)
+	if err != nil {
+		return nil, common.Address{}, err
+	}
+	return keyJSON, account.Address, nil
+}
+
+// generateBzzKey creates a fresh secp256k1 key and encrypts it with the given
+// passphrase, so a swarm node can be deployed without the operator supplying
+// a pre-existing bzzaccount.
+func generateBzzKey(passphrase string) ([]byte, common.Address, error) {
+	priv, err := crypto.GenerateKey()
+	if err != nil {
+		return nil, common.Address{}, err
+	}
+	return encryptBzzKey(priv, passphrase)
+}
+
+// importBzzKeyHex turns a raw hex-encoded private key (bzzkeyhex) into an
+// encrypted keystore JSON, so it can be fed through the same upload pipeline
+// as a pasted keystore file.
+func importBzzKeyHex(hexkey, passphrase string) ([]byte, common.Address, error) {
+	priv, err := crypto.HexToECDSA(strings.TrimPrefix(hexkey, "0x"))
+	if err != nil {
+		return nil, common.Address{}, err
+	}
+	return encryptBzzKey(priv, passphrase)
+}
+
 // deploysSwarm deploys a new Swarm node container to a remote machine via SSH,
 // docker and docker-compose. If an instance with the specified network name
 // already exists there, it will be overwritten!
@@ -82,27 +156,44 @@ func deploySwarm(client *sshClient, network string, swarmboot []string, config *
 	workdir := fmt.Sprintf("%d", rand.Int63())
 	files := make(map[string][]byte)
 
+	image := config.clientImage
+	if image == "" {
+		image = swarmKnownImage
+	}
+	version := config.clientVersion
+	if version == "" {
+		version = swarmKnownVersion
+	}
+
 	dockerfile := new(bytes.Buffer)
 	template.Must(template.New("").Parse(swarmDockerfile)).Execute(dockerfile, map[string]interface{}{
-		"NetworkID": config.network,
-		"Port":      config.port,
-		"Peers":     config.peersTotal,
-		"bzzPort":   config.bzzPort,
-		"SwarmBoot": strings.Join(swarmboot, ","),
-		"Unlock":    config.keyJSON != "",
-		"BzzAccount":config.bzzAccount,
+		"Image":       fmt.Sprintf("%s:%s", image, version),
+		"NetworkID":   config.network,
+		"Port":        config.port,
+		"Peers":       config.peersTotal,
+		"bzzPort":     config.bzzPort,
+		"SwarmBoot":   strings.Join(swarmboot, ","),
+		"Unlock":      config.keyJSON != "",
+		"BzzAccount":  config.bzzAccount,
+		"ExtraFlags":  strings.Join(config.extraFlags, " "),
+		"EnsAPIs":     config.ensAPIs,
+		"EnsDisabled": config.ensDisabled,
 	})
 	files[filepath.Join(workdir, "Dockerfile")] = dockerfile.Bytes()
 
 	composefile := new(bytes.Buffer)
 	template.Must(template.New("").Parse(swarmComposefile)).Execute(composefile, map[string]interface{}{
-		"Type":       kind,
-		"Datadir":    config.datadir,
-		"Network":    network,
-		"Port":       config.port,
-		"TotalPeers": config.peersTotal,
-		"BzzAccount": config.bzzAccount,
-
+		"Type":          kind,
+		"Datadir":       config.datadir,
+		"Network":       network,
+		"Port":          config.port,
+		"TotalPeers":    config.peersTotal,
+		"BzzAccount":    config.bzzAccount,
+		"ClientImage":   image,
+		"ClientVersion": version,
+		"ExtraFlags":    strings.Join(config.extraFlags, " "),
+		"EnsAPIs":       strings.Join(config.ensAPIs, ","),
+		"EnsDisabled":   config.ensDisabled,
 	})
 	files[filepath.Join(workdir, "docker-compose.yaml")] = composefile.Bytes()
 
@@ -127,26 +218,41 @@ func deploySwarm(client *sshClient, network string, swarmboot []string, config *
 // nodeInfos is returned from a boot or seal node status check to allow reporting
 // various configuration parameters.
 type swarmInfos struct {
-	genesis    []byte
-	network    int64
-	datadir    string
-	peersTotal int
-	port       int
-	bzzPort    int
-	enode      string
-	swarmenode string
-	bzzAccount  string
-	keyJSON    string
-	keyPass    string
+	genesis       []byte
+	network       int64
+	datadir       string
+	peersTotal    int
+	port          int
+	bzzPort       int
+	enode         string
+	swarmenode    string
+	bzzAccount    string
+	keyJSON       string
+	keyPass       string
+	clientImage   string
+	clientVersion string
+	extraFlags    []string
+	ensAPIs       []string
+	ensDisabled   bool
 }
 
 // Report converts the typed struct into a plain string->string map, containing
 // most - but not all - fields for reporting to the user.
 func (info *swarmInfos) Report() map[string]string {
 	report := map[string]string{
-		"Data directory":           info.datadir,
-		"Listener port":            strconv.Itoa(info.port),
-		"Peer count (all total)":   strconv.Itoa(info.peersTotal),
+		"Data directory":         info.datadir,
+		"Listener port":          strconv.Itoa(info.port),
+		"Peer count (all total)": strconv.Itoa(info.peersTotal),
+		"Client image":           info.clientImage,
+		"Client version":         info.clientVersion,
+	}
+	if len(info.extraFlags) > 0 {
+		report["Extra flags"] = strings.Join(info.extraFlags, " ")
+	}
+	if info.ensDisabled {
+		report["ENS resolution"] = "disabled"
+	} else if len(info.ensAPIs) > 0 {
+		report["ENS endpoints"] = strings.Join(info.ensAPIs, "\n")
 	}
 
 	report["Bzz account"] = info.bzzAccount
@@ -165,6 +271,120 @@ func (info *swarmInfos) Report() map[string]string {
 	return report
 }
 
+// swarmConfig is the TOML-serializable representation of a swarm deployment.
+// It lets an operator dump the parameters of a running (or about to be
+// deployed) node to disk and feed them back in on a later run, instead of
+// walking the interactive wizard every time.
+type swarmConfig struct {
+	Network       string   `toml:"network"`
+	Genesis       string   `toml:"genesis,omitempty"`
+	Bootnodes     []string `toml:"bootnodes,omitempty"`
+	Datadir       string   `toml:"datadir"`
+	Port          int      `toml:"port"`
+	BzzPort       int      `toml:"bzzport"`
+	PeersTotal    int      `toml:"maxpeers"`
+	BzzAccount    string   `toml:"bzzaccount,omitempty"`
+	KeyJSON       string   `toml:"keyjson,omitempty"`
+	KeyPass       string   `toml:"keypass,omitempty"`
+	ClientImage   string   `toml:"clientimage,omitempty"`
+	ClientVersion string   `toml:"clientversion,omitempty"`
+	ExtraFlags    []string `toml:"extraflags,omitempty"`
+	EnsAPIs       []string `toml:"ensapis,omitempty"`
+	EnsDisabled   bool     `toml:"ensdisabled,omitempty"`
+}
+
+// newSwarmConfig assembles a swarmConfig out of the live deployment state so
+// it can be handed to dumpSwarmConfig.
+func newSwarmConfig(network string, bootnodes []string, infos *swarmInfos) *swarmConfig {
+	return &swarmConfig{
+		Network:       network,
+		Genesis:       string(infos.genesis),
+		Bootnodes:     bootnodes,
+		Datadir:       infos.datadir,
+		Port:          infos.port,
+		BzzPort:       infos.bzzPort,
+		PeersTotal:    infos.peersTotal,
+		BzzAccount:    infos.bzzAccount,
+		KeyJSON:       infos.keyJSON,
+		KeyPass:       infos.keyPass,
+		ClientImage:   infos.clientImage,
+		ClientVersion: infos.clientVersion,
+		ExtraFlags:    infos.extraFlags,
+		EnsAPIs:       infos.ensAPIs,
+		EnsDisabled:   infos.ensDisabled,
+	}
+}
+
+// mergeInto overlays the fields set in cfg onto an already-defaulted
+// swarmInfos, so a config file only needs to mention the parameters it wants
+// to override and everything else keeps falling back to the built-in
+// defaults instead of being zeroed out.
+func (cfg *swarmConfig) mergeInto(infos *swarmInfos) {
+	if cfg.Genesis != "" {
+		infos.genesis = []byte(cfg.Genesis)
+	}
+	if cfg.Datadir != "" {
+		infos.datadir = cfg.Datadir
+	}
+	if cfg.Port != 0 {
+		infos.port = cfg.Port
+	}
+	if cfg.BzzPort != 0 {
+		infos.bzzPort = cfg.BzzPort
+	}
+	if cfg.PeersTotal != 0 {
+		infos.peersTotal = cfg.PeersTotal
+	}
+	if cfg.BzzAccount != "" {
+		infos.bzzAccount = cfg.BzzAccount
+	}
+	if cfg.KeyJSON != "" {
+		infos.keyJSON = cfg.KeyJSON
+	}
+	if cfg.KeyPass != "" {
+		infos.keyPass = cfg.KeyPass
+	}
+	if cfg.ClientImage != "" {
+		infos.clientImage = cfg.ClientImage
+	}
+	if cfg.ClientVersion != "" {
+		infos.clientVersion = cfg.ClientVersion
+	}
+	if len(cfg.ExtraFlags) > 0 {
+		infos.extraFlags = cfg.ExtraFlags
+	}
+	if len(cfg.EnsAPIs) > 0 {
+		infos.ensAPIs = cfg.EnsAPIs
+	}
+	if cfg.EnsDisabled {
+		infos.ensDisabled = true
+	}
+}
+
+// loadSwarmConfig reads and parses a swarm deployment config previously
+// written by dumpSwarmConfig (or hand-edited by the operator).
+func loadSwarmConfig(path string) (*swarmConfig, error) {
+	blob, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	cfg := new(swarmConfig)
+	if err := toml.Unmarshal(blob, cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// dumpSwarmConfig serializes a swarmConfig to disk as TOML so it can be
+// reused (and hand-tweaked) on a future deployment.
+func dumpSwarmConfig(path string, cfg *swarmConfig) error {
+	blob, err := toml.Marshal(*cfg)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, blob, 0644)
+}
+
 // checkNode does a health-check against an boot or seal node server to verify
 // whether it's running, and if yes, whether it's responsive.
 func checkSwarmNode(client *sshClient, network string, boot bool) (*swarmInfos, error) {
@@ -214,15 +434,28 @@ func checkSwarmNode(client *sshClient, network string, boot bool) (*swarmInfos,
 	//	log.Warn(fmt.Sprintf("%s bzzp2p port seems unreachable", strings.Title(kind)), "server", client.server, "bzzport", bzzPort, "err", err)
 	//}
 	// Assemble and return the useful infos
+	var extraFlags []string
+	if raw := infos.envvars["EXTRA_FLAGS"]; raw != "" {
+		extraFlags = strings.Fields(raw)
+	}
+	var ensAPIs []string
+	if raw := infos.envvars["ENS_APIS"]; raw != "" {
+		ensAPIs = strings.Split(raw, ",")
+	}
 	stats := &swarmInfos{
 		genesis:    genesis,
 		datadir:    infos.volumes["/root/.ethereum"],
 		port:       port,
 		//bzzPort:    bzzPort,
-		peersTotal: totalPeers,
-		keyJSON:    keyJSON,
-		bzzAccount: infos.envvars["BZZ_NAME"],
-		keyPass:    keyPass,
+		peersTotal:    totalPeers,
+		keyJSON:       keyJSON,
+		bzzAccount:    infos.envvars["BZZ_NAME"],
+		keyPass:       keyPass,
+		clientImage:   infos.envvars["CLIENT_IMAGE"],
+		clientVersion: infos.envvars["CLIENT_VERSION"],
+		extraFlags:    extraFlags,
+		ensAPIs:       ensAPIs,
+		ensDisabled:   infos.envvars["ENS_DISABLED"] == "true",
 	}
 	stats.swarmenode = fmt.Sprintf("enode://%s@%s:%d", id, client.address, stats.port)
 	//fmt.Println(nil)
@@ -0,0 +1,86 @@
+// Copyright 2017 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// collectDashboardEntries walks the wizard's known servers, gathering the
+// swarm enode links and any ethstats/explorer/faucet endpoints to aggregate
+// onto the dashboard.
+func (w *wizard) collectDashboardEntries() *dashboardEntries {
+	entries := &dashboardEntries{Network: w.network}
+
+	for _, client := range w.servers {
+		if client == nil {
+			continue
+		}
+		if infos, err := checkSwarmNode(client, w.network, true); err == nil {
+			entries.Enodes = append(entries.Enodes, infos.swarmenode)
+		}
+		if infos, err := checkSwarmNode(client, w.network, false); err == nil {
+			entries.Enodes = append(entries.Enodes, infos.swarmenode)
+		}
+		if infos, err := checkEthstats(client, w.network); err == nil {
+			entries.Ethstats = fmt.Sprintf("http://%s:%d", infos.host, infos.port)
+		}
+		if infos, err := checkExplorer(client, w.network); err == nil {
+			entries.Explorer = fmt.Sprintf("http://%s:%d", infos.host, infos.port)
+		}
+		if infos, err := checkFaucet(client, w.network); err == nil {
+			entries.Faucet = fmt.Sprintf("http://%s:%d", infos.host, infos.port)
+		}
+	}
+	return entries
+}
+
+// deployDashboard creates a new dashboard configuration based on some user
+// input.
+//
+// TODO: not yet reachable from any menu — wire into wizard.go's run() deploy
+// submenu (alongside the node deployers) once that file is back in this tree.
+func (w *wizard) deployDashboard() {
+	// Select the server to interact with
+	server := w.selectServer()
+	if server == "" {
+		return
+	}
+	client := w.servers[server]
+
+	// Retrieve any active dashboard configurations from the server
+	infos, err := checkDashboard(client, w.network)
+	if err != nil {
+		infos = &dashboardInfos{port: 80}
+	}
+
+	fmt.Println()
+	fmt.Printf("Which port should the dashboard listen on? (default = %d)\n", infos.port)
+	infos.port = w.readDefaultInt(infos.port)
+
+	entries := w.collectDashboardEntries()
+	if out, err := deployDashboard(client, w.network, infos.port, entries); err != nil {
+		log.Error("Failed to deploy dashboard container", "err", err)
+		if len(out) > 0 {
+			fmt.Printf("%s\n", out)
+		}
+		return
+	}
+	w.networkStats()
+}
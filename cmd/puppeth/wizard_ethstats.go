@@ -0,0 +1,69 @@
+// Copyright 2017 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// deployEthstats creates a new ethstats monitoring component based on some
+// user input.
+//
+// TODO: not yet reachable from any menu — wire into wizard.go's run() deploy
+// submenu (alongside the node deployers) once that file is back in this tree.
+func (w *wizard) deployEthstats() {
+	// Select the server to interact with
+	server := w.selectServer()
+	if server == "" {
+		return
+	}
+	client := w.servers[server]
+
+	// Retrieve any active ethstats configurations from the server
+	infos, err := checkEthstats(client, w.network)
+	if err != nil {
+		infos = &ethstatsInfos{port: 3000, secret: randomPassphrase()}
+	}
+
+	fmt.Println()
+	fmt.Printf("Which port should ethstats listen on? (default = %d)\n", infos.port)
+	infos.port = w.readDefaultInt(infos.port)
+
+	fmt.Println()
+	fmt.Printf("What should be the secret for node reports? (default = %s)\n", infos.secret)
+	infos.secret = w.readDefaultString(infos.secret)
+
+	fmt.Println()
+	fmt.Println("Which nodes should be banned from reporting? (comma separated, default = none)")
+	banned := strings.Join(infos.banned, ",")
+	infos.banned = strings.Split(w.readDefaultString(banned), ",")
+	if len(infos.banned) == 1 && infos.banned[0] == "" {
+		infos.banned = nil
+	}
+
+	if out, err := deployEthstats(client, w.network, infos.port, infos.secret, infos.banned); err != nil {
+		log.Error("Failed to deploy ethstats container", "err", err)
+		if len(out) > 0 {
+			fmt.Printf("%s\n", out)
+		}
+		return
+	}
+	w.networkStats()
+}
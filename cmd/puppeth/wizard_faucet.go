@@ -0,0 +1,88 @@
+// Copyright 2017 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// deployFaucet creates a new faucet configuration based on some user input.
+//
+// TODO: not yet reachable from any menu — wire into wizard.go's run() deploy
+// submenu (alongside the node deployers) once that file is back in this tree.
+func (w *wizard) deployFaucet() {
+	if w.conf.Genesis == nil {
+		log.Error("No genesis block configured")
+		return
+	}
+	// Select the server to interact with
+	server := w.selectServer()
+	if server == "" {
+		return
+	}
+	client := w.servers[server]
+
+	// Retrieve any active faucet configurations from the server
+	infos, err := checkFaucet(client, w.network)
+	if err != nil {
+		infos = &faucetInfos{port: 80, amount: 1, minutes: 1440, tiers: 3}
+	}
+	infos.genesis, _ = json.MarshalIndent(w.conf.Genesis, "", "  ")
+	infos.networkID = w.conf.Genesis.Config.ChainId.Int64()
+
+	fmt.Println()
+	fmt.Printf("Which port should the faucet listen on? (default = %d)\n", infos.port)
+	infos.port = w.readDefaultInt(infos.port)
+
+	fmt.Println()
+	fmt.Printf("How many Ethers to release per funding round? (default = %d)\n", infos.amount)
+	infos.amount = w.readDefaultInt(infos.amount)
+
+	fmt.Println()
+	fmt.Printf("How many minutes to enforce as a cooldown between funding rounds? (default = %d)\n", infos.minutes)
+	infos.minutes = w.readDefaultInt(infos.minutes)
+
+	fmt.Println()
+	fmt.Printf("How many funding tiers to offer (x1, x2, x5, ...)? (default = %d)\n", infos.tiers)
+	infos.tiers = w.readDefaultInt(infos.tiers)
+
+	fmt.Println()
+	fmt.Println("Please paste the faucet's funding account's key JSON:")
+	infos.keyJSON = w.readJSON()
+
+	fmt.Println()
+	fmt.Println("What's the unlock password for the account? (won't be echoed)")
+	infos.keyPass = w.readPassword()
+
+	if _, err := keystore.DecryptKey([]byte(infos.keyJSON), infos.keyPass); err != nil {
+		log.Error("Failed to decrypt key with given passphrase")
+		return
+	}
+
+	if out, err := deployFaucet(client, w.network, w.conf.bootnodes, infos); err != nil {
+		log.Error("Failed to deploy faucet container", "err", err)
+		if len(out) > 0 {
+			fmt.Printf("%s\n", out)
+		}
+		return
+	}
+	w.networkStats()
+}
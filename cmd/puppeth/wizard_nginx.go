@@ -0,0 +1,62 @@
+// Copyright 2017 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// deployNginx creates a new nginx reverse proxy configuration based on some
+// user input, routing each vhost the user enters to the local port given for
+// it on the selected server.
+//
+// TODO: not yet reachable from any menu — wire into wizard.go's run() deploy
+// submenu (alongside the node deployers) once that file is back in this tree.
+func (w *wizard) deployNginx() {
+	// Select the server to interact with
+	server := w.selectServer()
+	if server == "" {
+		return
+	}
+	client := w.servers[server]
+
+	var vhosts []nginxVhost
+	for {
+		fmt.Println()
+		fmt.Println("Which vhost should be routed (e.g. ethstats.example.com)? (leave empty to finish)")
+		host := w.readDefaultString("")
+		if host == "" {
+			break
+		}
+
+		fmt.Println()
+		fmt.Println("Which local port should it be proxied to?")
+		port := w.readInt()
+
+		vhosts = append(vhosts, nginxVhost{Host: host, Port: port})
+	}
+	if out, err := deployNginx(client, w.network, vhosts); err != nil {
+		log.Error("Failed to deploy nginx container", "err", err)
+		if len(out) > 0 {
+			fmt.Printf("%s\n", out)
+		}
+		return
+	}
+	w.networkStats()
+}
@@ -19,6 +19,9 @@ package main
 import (
 	"encoding/json"
 	"fmt"
+	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/ethereum/go-ethereum/accounts/keystore"
@@ -26,8 +29,76 @@ import (
 	"github.com/ethereum/go-ethereum/log"
 )
 
+// applySwarmEnv overlays any BZZ_* environment variables onto infos, letting
+// operators pin parameters for a fleet of servers without editing a config
+// file on each of them. Config file values are applied beforehand, so env
+// vars take priority over the file but still yield to interactive input.
+func applySwarmEnv(infos *swarmInfos) {
+	if v := os.Getenv("BZZ_DATADIR"); v != "" {
+		infos.datadir = v
+	}
+	if v := os.Getenv("BZZ_PORT"); v != "" {
+		if port, err := strconv.Atoi(v); err == nil {
+			infos.port = port
+		}
+	}
+	if v := os.Getenv("BZZ_BZZPORT"); v != "" {
+		if port, err := strconv.Atoi(v); err == nil {
+			infos.bzzPort = port
+		}
+	}
+	if v := os.Getenv("BZZ_MAXPEERS"); v != "" {
+		if peers, err := strconv.Atoi(v); err == nil {
+			infos.peersTotal = peers
+		}
+	}
+	if v := os.Getenv("BZZ_ACCOUNT"); v != "" {
+		infos.bzzAccount = v
+	}
+	if v := os.Getenv("BZZ_CLIENT_IMAGE"); v != "" {
+		infos.clientImage = v
+	}
+	if v := os.Getenv("BZZ_CLIENT_VERSION"); v != "" {
+		infos.clientVersion = v
+	}
+	if v := os.Getenv("BZZ_EXTRA_FLAGS"); v != "" {
+		infos.extraFlags = strings.Fields(v)
+	}
+	if v := os.Getenv("BZZ_ENS_APIS"); v != "" {
+		infos.ensAPIs = strings.Split(v, ",")
+	}
+	if v := os.Getenv("BZZ_ENS_DISABLED"); v != "" {
+		infos.ensDisabled = v == "true"
+	}
+}
+
+// readSwarmPassphrase prompts for a passphrase to protect a new or imported
+// bzzaccount key, auto-generating and printing one if the user leaves it
+// empty so the key isn't left unencrypted.
+func (w *wizard) readSwarmPassphrase(kind string) string {
+	fmt.Println()
+	fmt.Printf("What passphrase should protect the %s bzzaccount key? (leave empty to auto-generate one)\n", kind)
+	pass := w.readDefaultString("")
+	if pass == "" {
+		pass = randomPassphrase()
+		log.Info(fmt.Sprintf("Generated passphrase for %s bzzaccount key", kind), "passphrase", pass)
+	}
+	return pass
+}
+
 // deploySwarm creates a new node configuration based on some user input.
-func (w *wizard) deploySwarm(boot bool) {
+// configFile, if non-empty, is loaded and merged in before the interactive
+// prompts so operators can reproduce a swarm deployment without re-entering
+// every parameter by hand. Priority, lowest to highest, is: hard-coded
+// defaults, configFile, BZZ_* environment variables, interactive input.
+// If a node is already running on the selected server, its current
+// parameters are used as the defaults and neither configFile nor the
+// environment is consulted.
+//
+// TODO: configFile has no caller yet — wire a --config flag through to here
+// (and a menu entry for dumpconfig below) once main.go/wizard.go are back in
+// this tree.
+func (w *wizard) deploySwarm(boot bool, configFile string) {
 	// Do some sanity check before the user wastes time on input
 	if w.conf.Genesis == nil {
 		log.Error("No genesis block configured")
@@ -52,6 +123,18 @@ func (w *wizard) deploySwarm(boot bool) {
 	}
 	existed := err == nil
 
+	if !existed && configFile != "" {
+		cfg, err := loadSwarmConfig(configFile)
+		if err != nil {
+			log.Error("Failed to load swarm config file", "file", configFile, "err", err)
+			return
+		}
+		cfg.mergeInto(infos)
+	}
+	if !existed {
+		applySwarmEnv(infos)
+	}
+
 	infos.genesis, _ = json.MarshalIndent(w.conf.Genesis, "", "  ")
 	infos.network = w.conf.Genesis.Config.ChainId.Int64()
 
@@ -80,31 +163,97 @@ func (w *wizard) deploySwarm(boot bool) {
 	fmt.Printf("How many peers to allow connecting? (default = %d)\n", infos.peersTotal)
 	infos.peersTotal = w.readDefaultInt(infos.peersTotal)
 
-	if infos.keyJSON == "" {
-		fmt.Println()
-		fmt.Println("Please paste the bzzaccount's key JSON:")
-		infos.keyJSON = w.readJSON()
+	// Figure out which client image/version to run, and any extra flags
+	if infos.clientImage == "" {
+		infos.clientImage = swarmKnownImage
+	}
+	if infos.clientVersion == "" {
+		infos.clientVersion = swarmKnownVersion
+	}
+	fmt.Println()
+	fmt.Printf("Which client image to run? (default = %s)\n", infos.clientImage)
+	infos.clientImage = w.readDefaultString(infos.clientImage)
 
-		fmt.Println()
-		fmt.Println("What's the unlock password for the account? (won't be echoed)")
-		infos.keyPass = w.readPassword()
+	fmt.Println()
+	fmt.Printf("Which client version/tag to run? (default = %s)\n", infos.clientVersion)
+	infos.clientVersion = w.readDefaultString(infos.clientVersion)
 
-		if _, err := keystore.DecryptKey([]byte(infos.keyJSON), infos.keyPass); err != nil {
-			log.Error("Failed to decrypt key with given passphrase")
-			return
-		}
+	fmt.Println()
+	fmt.Printf("Any extra flags to pass to swarm (e.g. --verbosity, --nat, --sync-mode)? (default = %s)\n", strings.Join(infos.extraFlags, " "))
+	if extra := w.readDefaultString(strings.Join(infos.extraFlags, " ")); extra != "" {
+		infos.extraFlags = strings.Fields(extra)
+	} else {
+		infos.extraFlags = nil
 	}
 
+	// Figure out which ENS resolver endpoints to wire up, if any
+	ensDefault := "no"
+	if infos.ensDisabled {
+		ensDefault = "yes"
+	}
 	fmt.Println()
-	if infos.bzzAccount == "" {
-		fmt.Println("Please paste the bzzaccount:")
-		for {
-			if address := w.readAddress(); address != nil {
-				infos.bzzAccount = address.Hex()
-				break
+	fmt.Printf("Should ENS resolution be disabled (y/n)? (default = %s)\n", ensDefault)
+	infos.ensDisabled = w.readDefaultString(ensDefault[:1]) != "n"
+
+	if !infos.ensDisabled {
+		fmt.Println()
+		fmt.Printf("Which --ens-api endpoints to use (tld:contract@rpc-url or contract@ipc-path, comma separated)? (default = %s)\n", strings.Join(infos.ensAPIs, ","))
+		if raw := w.readDefaultString(strings.Join(infos.ensAPIs, ",")); raw != "" {
+			infos.ensAPIs = strings.Split(raw, ",")
+		} else {
+			infos.ensAPIs = nil
+		}
+	}
+
+	if infos.keyJSON == "" && infos.bzzAccount == "" {
+		fmt.Println()
+		fmt.Println("Please paste the bzzaccount's key JSON, its raw hex private key (bzzkeyhex), or leave it empty to auto-generate one:")
+		input := w.readDefaultString("")
+
+		switch {
+		case input == "":
+			pass := w.readSwarmPassphrase("new")
+
+			keyJSON, address, err := generateBzzKey(pass)
+			if err != nil {
+				log.Error("Failed to generate bzzaccount key", "err", err)
+				return
 			}
+			infos.keyJSON, infos.keyPass, infos.bzzAccount = string(keyJSON), pass, address.Hex()
+
+		case json.Valid([]byte(input)):
+			infos.keyJSON = input
+
+			fmt.Println()
+			fmt.Println("What's the unlock password for the account? (won't be echoed)")
+			infos.keyPass = w.readPassword()
+
+			key, err := keystore.DecryptKey([]byte(infos.keyJSON), infos.keyPass)
+			if err != nil {
+				log.Error("Failed to decrypt key with given passphrase")
+				return
+			}
+			infos.bzzAccount = key.Address.Hex()
+
+		default:
+			pass := w.readSwarmPassphrase("imported")
+
+			keyJSON, address, err := importBzzKeyHex(input, pass)
+			if err != nil {
+				log.Error("Failed to import bzzkeyhex", "err", err)
+				return
+			}
+			infos.keyJSON, infos.keyPass, infos.bzzAccount = string(keyJSON), pass, address.Hex()
+		}
+	} else if infos.bzzAccount == "" {
+		key, err := keystore.DecryptKey([]byte(infos.keyJSON), infos.keyPass)
+		if err != nil {
+			log.Error("Failed to decrypt key with given passphrase")
+			return
 		}
+		infos.bzzAccount = key.Address.Hex()
 	} else {
+		fmt.Println()
 		fmt.Printf("Please paste the bzzaccount (default = %s)\n", infos.bzzAccount)
 		infos.bzzAccount = w.readDefaultAddress(common.HexToAddress(infos.bzzAccount)).Hex()
 	}
@@ -129,3 +278,33 @@ func (w *wizard) deploySwarm(boot bool) {
 
 	w.networkStats()
 }
+
+// dumpconfig writes the currently deployed parameters of a swarm node, along
+// with the genesis the node runs and the bootnodes tracked by the wizard, to a
+// TOML file that can later be handed back to deploySwarm as its configFile to
+// reproduce the same node without walking through the prompts again.
+func (w *wizard) dumpconfig() {
+	// Select the server whose configuration should be dumped
+	server := w.selectServer()
+	if server == "" {
+		return
+	}
+	client := w.servers[server]
+
+	infos, err := checkSwarmNode(client, w.network, false)
+	if err != nil {
+		log.Error("Failed to retrieve swarm node configuration", "err", err)
+		return
+	}
+
+	fmt.Println()
+	fmt.Println("Where should the config file be saved?")
+	path := w.readString()
+
+	cfg := newSwarmConfig(w.network, w.conf.bootnodes, infos)
+	if err := dumpSwarmConfig(path, cfg); err != nil {
+		log.Error("Failed to write swarm config file", "file", path, "err", err)
+		return
+	}
+	log.Info("Swarm config written", "file", path)
+}